@@ -0,0 +1,23 @@
+// Package cmd assembles the `hasura` root command from every command group
+// under cli/cmd/.
+package cmd
+
+import (
+	"github.com/hasura/graphql-engine/cli/v2"
+	"github.com/hasura/graphql-engine/cli/v2/cmd/schema"
+
+	"github.com/spf13/cobra"
+)
+
+// NewRootCmd returns the root `hasura` command with every command group
+// registered on it.
+func NewRootCmd(ec *cli.ExecutionContext) *cobra.Command {
+	rootCmd := &cobra.Command{
+		Use:   "hasura",
+		Short: "Hasura GraphQL Engine command line client",
+	}
+	rootCmd.AddCommand(
+		schema.NewSchemaCmd(ec),
+	)
+	return rootCmd
+}
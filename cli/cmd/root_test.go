@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/hasura/graphql-engine/cli/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRootCmd_schemaCommandIsReachable(t *testing.T) {
+	rootCmd := NewRootCmd(&cli.ExecutionContext{})
+
+	snapshotCmd, _, err := rootCmd.Find([]string{"schema", "snapshot"})
+	assert.NoError(t, err)
+	assert.Equal(t, "snapshot", snapshotCmd.Name())
+
+	diffCmd, _, err := rootCmd.Find([]string{"schema", "diff"})
+	assert.NoError(t, err)
+	assert.Equal(t, "diff", diffCmd.Name())
+}
@@ -0,0 +1,108 @@
+package schema
+
+import (
+	"fmt"
+
+	graphqlschemaintrospection "github.com/hasura/graphql-engine/cli/v2/internal/metadataobject/graphql_schema_introspection"
+	"github.com/hasura/graphql-engine/cli/v2/internal/metadataobject/graphql_schema_introspection/schemasnapshot"
+
+	"github.com/hasura/graphql-engine/cli/v2"
+	"github.com/spf13/cobra"
+)
+
+type schemaDiffOptions struct {
+	EC          *cli.ExecutionContext
+	SchemaDrift string
+}
+
+func newSchemaDiffCmd(ec *cli.ExecutionContext) *cobra.Command {
+	opts := &schemaDiffOptions{EC: ec}
+	diffCmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Diff the stored schema snapshots against the engine's current schema",
+		Long: `Re-run introspection for every role covered by graphql_schema_introspection.yaml
+and diff the result against the snapshot stored in metadata/schema_snapshots,
+reporting added/removed types, fields, arguments and directives.
+
+This is a standalone check: run it yourself (e.g. in CI, before merging a
+metadata change) as it is not currently invoked by "hasura metadata apply".`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.run()
+		},
+	}
+	diffCmd.Flags().StringVar(&opts.SchemaDrift, "schema-drift", string(schemasnapshot.DriftModeWarn),
+		"how to treat breaking schema drift for roles with introspection enabled: warn, error or ignore")
+	return diffCmd
+}
+
+func (o *schemaDiffOptions) run() error {
+	mode := schemasnapshot.DriftMode(o.SchemaDrift)
+	switch mode {
+	case schemasnapshot.DriftModeWarn, schemasnapshot.DriftModeError, schemasnapshot.DriftModeIgnore:
+	default:
+		return fmt.Errorf("invalid --schema-drift value %q: must be one of warn, error, ignore", o.SchemaDrift)
+	}
+
+	breaking, err := CheckDrift(o.EC)
+	if err != nil {
+		return err
+	}
+
+	if len(breaking) == 0 || mode == schemasnapshot.DriftModeIgnore {
+		return nil
+	}
+	if mode == schemasnapshot.DriftModeWarn {
+		for _, diff := range breaking {
+			o.EC.Logger.Warnf("breaking schema drift detected: %s", diff.String())
+		}
+		return nil
+	}
+	return fmt.Errorf("breaking schema drift detected for %d role(s) with introspection enabled", len(breaking))
+}
+
+// CheckDrift re-runs introspection for every role covered by
+// graphql_schema_introspection.yaml and returns the breaking diffs against
+// their stored snapshots, applying the same disabled_for_roles exemption
+// "hasura schema diff" does. It is exported, independent of --schema-drift's
+// warn/error/ignore presentation, specifically so that "hasura metadata
+// apply" can call it directly as soon as apply gains a hook for metadata
+// object checks — see the package comment in schema.go.
+func CheckDrift(ec *cli.ExecutionContext) ([]*schemasnapshot.Diff, error) {
+	m := graphqlschemaintrospection.New(ec.MetadataDir, ec.Logger)
+	metadata, err := buildProjectMetadata(ec.MetadataDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.Validate(metadata); err != nil {
+		return nil, err
+	}
+
+	var breaking []*schemasnapshot.Diff
+	for _, role := range m.RolesForIntrospectionCheck(metadata) {
+		oldSnapshot, err := schemasnapshot.Load(ec.MetadataDir, role)
+		if err != nil {
+			return nil, fmt.Errorf("loading existing snapshot for role %q: %w", role, err)
+		}
+		introspectionResult, err := runIntrospectionQuery(ec, role)
+		if err != nil {
+			return nil, fmt.Errorf("introspecting schema for role %q: %w", role, err)
+		}
+		newSnapshot, err := schemasnapshot.Canonicalize(introspectionResult)
+		if err != nil {
+			return nil, fmt.Errorf("canonicalizing introspection result for role %q: %w", role, err)
+		}
+		diff, err := schemasnapshot.CompareSnapshots(role, oldSnapshot, newSnapshot)
+		if err != nil {
+			return nil, fmt.Errorf("diffing snapshot for role %q: %w", role, err)
+		}
+		ec.Logger.Info(diff.String())
+		if !diff.IsBreaking() {
+			continue
+		}
+		if m.IntrospectionDisabledForRole(metadata, role) {
+			continue
+		}
+		breaking = append(breaking, diff)
+	}
+	return breaking, nil
+}
@@ -0,0 +1,89 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/hasura/graphql-engine/cli/v2"
+)
+
+const introspectionQuery = `
+query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types {
+      kind
+      name
+      fields(includeDeprecated: true) {
+        name
+        isDeprecated
+        type { ...TypeRef }
+        args { name defaultValue type { ...TypeRef } }
+      }
+      inputFields { name defaultValue type { ...TypeRef } }
+      interfaces { name }
+      enumValues(includeDeprecated: true) { name isDeprecated }
+      possibleTypes { name }
+    }
+    directives {
+      name
+      args { name defaultValue type { ...TypeRef } }
+    }
+  }
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType {
+    kind
+    name
+    ofType {
+      kind
+      name
+      ofType {
+        kind
+        name
+      }
+    }
+  }
+}`
+
+// runIntrospectionQuery runs the standard GraphQL introspection query
+// against the engine as role and returns the raw `data` object, ready to be
+// canonicalized by schemasnapshot.Canonicalize.
+func runIntrospectionQuery(ec *cli.ExecutionContext, role string) ([]byte, error) {
+	body, err := json.Marshal(map[string]string{"query": introspectionQuery})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, ec.Config.ServerConfig.GetGraphQLEndpoint(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if role != "" {
+		req.Header.Set("X-Hasura-Role", role)
+	}
+	resp, err := ec.Config.ServerConfig.GetHTTPClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var parsed struct {
+		Data   json.RawMessage `json:"data"`
+		Errors json.RawMessage `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("decoding introspection response: %w", err)
+	}
+	if len(parsed.Errors) > 0 {
+		return nil, fmt.Errorf("introspection query returned errors: %s", parsed.Errors)
+	}
+	return parsed.Data, nil
+}
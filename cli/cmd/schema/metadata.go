@@ -0,0 +1,63 @@
+package schema
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	graphqlschemaintrospection "github.com/hasura/graphql-engine/cli/v2/internal/metadataobject/graphql_schema_introspection"
+	"github.com/hasura/graphql-engine/cli/v2/internal/metadataobject/graphql_schema_introspection/schemasnapshot"
+
+	"gopkg.in/yaml.v3"
+)
+
+// buildProjectMetadata reads every metadata YAML file under metadataDir into
+// a map keyed the way MetadataObject.Build keys its output and Export and
+// Validate read their input
+// (graphql_schema_introspection.yaml becomes graphqlschemaintrospection.MetadataObjectKey,
+// every other file keys off its path relative to metadataDir). Validate and
+// RolesForIntrospectionCheck need to see roles declared in actions.yaml,
+// table permission files, inherited_roles.yaml, etc. — not just
+// graphql_schema_introspection.yaml — so schema snapshot/diff must read the
+// whole metadata directory rather than call MetadataObject.Build alone.
+func buildProjectMetadata(metadataDir string) (map[string]yaml.Node, error) {
+	metadata := map[string]yaml.Node{}
+	err := filepath.Walk(metadataDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if filepath.Base(path) == schemasnapshot.DirName {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		ext := filepath.Ext(path)
+		if ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+		rel, err := filepath.Rel(metadataDir, path)
+		if err != nil {
+			return err
+		}
+		bs, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var node yaml.Node
+		if err := yaml.Unmarshal(bs, &node); err != nil {
+			return fmt.Errorf("unmarshalling %s: %w", rel, err)
+		}
+		key := rel
+		if rel == graphqlschemaintrospection.MetadataFilename {
+			key = graphqlschemaintrospection.MetadataObjectKey
+		}
+		metadata[key] = node
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading metadata directory %s: %w", metadataDir, err)
+	}
+	return metadata, nil
+}
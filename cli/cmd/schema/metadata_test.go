@@ -0,0 +1,36 @@
+package schema
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	graphqlschemaintrospection "github.com/hasura/graphql-engine/cli/v2/internal/metadataobject/graphql_schema_introspection"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildProjectMetadata_validatesAndChecksRolesDeclaredInOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, graphqlschemaintrospection.MetadataFilename), []byte("disabled_for_roles: [user]\n"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "actions.yaml"), []byte("actions:\n  - name: doThing\n    permissions:\n      - role: user\n"), 0644))
+
+	metadata, err := buildProjectMetadata(dir)
+	assert.NoError(t, err)
+
+	m := graphqlschemaintrospection.New(dir, logrus.New())
+	assert.NoError(t, m.Validate(metadata), "role declared in actions.yaml must not be reported as unknown")
+	assert.Equal(t, []string{"user"}, m.RolesForIntrospectionCheck(metadata))
+}
+
+func TestBuildProjectMetadata_reportsTrulyUnknownRole(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, graphqlschemaintrospection.MetadataFilename), []byte("disabled_for_roles: [typo]\n"), 0644))
+
+	metadata, err := buildProjectMetadata(dir)
+	assert.NoError(t, err)
+
+	m := graphqlschemaintrospection.New(dir, logrus.New())
+	assert.Error(t, m.Validate(metadata))
+}
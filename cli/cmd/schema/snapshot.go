@@ -0,0 +1,53 @@
+package schema
+
+import (
+	"fmt"
+
+	graphqlschemaintrospection "github.com/hasura/graphql-engine/cli/v2/internal/metadataobject/graphql_schema_introspection"
+	"github.com/hasura/graphql-engine/cli/v2/internal/metadataobject/graphql_schema_introspection/schemasnapshot"
+
+	"github.com/hasura/graphql-engine/cli/v2"
+	"github.com/spf13/cobra"
+)
+
+type schemaSnapshotOptions struct {
+	EC *cli.ExecutionContext
+}
+
+func newSchemaSnapshotCmd(ec *cli.ExecutionContext) *cobra.Command {
+	opts := &schemaSnapshotOptions{EC: ec}
+	snapshotCmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Take a schema snapshot for every role gated by graphql_schema_introspection.yaml",
+		Long: `Run the standard GraphQL introspection query against the engine for every
+role listed or implied by graphql_schema_introspection.yaml, and store the
+canonicalized result under metadata/schema_snapshots/<role>.json.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.run()
+		},
+	}
+	return snapshotCmd
+}
+
+func (o *schemaSnapshotOptions) run() error {
+	m := graphqlschemaintrospection.New(o.EC.MetadataDir, o.EC.Logger)
+	metadata, err := buildProjectMetadata(o.EC.MetadataDir)
+	if err != nil {
+		return err
+	}
+	if err := m.Validate(metadata); err != nil {
+		return err
+	}
+	roles := m.RolesForIntrospectionCheck(metadata)
+	for _, role := range roles {
+		result, err := runIntrospectionQuery(o.EC, role)
+		if err != nil {
+			return fmt.Errorf("introspecting schema for role %q: %w", role, err)
+		}
+		if err := schemasnapshot.Save(o.EC.MetadataDir, role, result); err != nil {
+			return fmt.Errorf("saving snapshot for role %q: %w", role, err)
+		}
+		o.EC.Logger.Infof("saved schema snapshot for role %q", role)
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+package graphqlschemaintrospection
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/hasura/graphql-engine/cli/v2/internal/metadataobject/graphql_schema_introspection/schemasnapshot"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// MetadataFilename is the file graphql_schema_introspection is read from and
+// written to, relative to the metadata directory.
+const MetadataFilename = "graphql_schema_introspection.yaml"
+
+// MetadataObjectKey is the key this object is stored under in the assembled
+// metadata map passed between MetadataObject.Build/Export/Validate.
+const MetadataObjectKey = "graphql_schema_introspection"
+
+type MetadataObject struct {
+	MetadataDir string
+	logger      *logrus.Logger
+}
+
+func New(baseDir string, logger *logrus.Logger) *MetadataObject {
+	return &MetadataObject{
+		MetadataDir: baseDir,
+		logger:      logger,
+	}
+}
+
+func (m *MetadataObject) filepath() string {
+	return filepath.Join(m.MetadataDir, MetadataFilename)
+}
+
+func (m *MetadataObject) Build() (map[string]yaml.Node, error) {
+	data := map[string]yaml.Node{}
+	bs, err := ioutil.ReadFile(m.filepath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return data, nil
+		}
+		return nil, fmt.Errorf("reading %s: %w", m.filepath(), err)
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(bs, &node); err != nil {
+		return nil, fmt.Errorf("unmarshalling %s: %w", m.filepath(), err)
+	}
+	data[MetadataObjectKey] = node
+	return data, nil
+}
+
+func (m *MetadataObject) Export(metadata map[string]yaml.Node) (map[string][]byte, error) {
+	out := map[string][]byte{}
+	if node, ok := metadata[MetadataObjectKey]; ok {
+		bs, err := yaml.Marshal(&node)
+		if err != nil {
+			return nil, fmt.Errorf("marshalling %s: %w", MetadataFilename, err)
+		}
+		out[m.filepath()] = bs
+	}
+	snapshots, err := schemasnapshot.Export(m.MetadataDir)
+	if err != nil {
+		return nil, fmt.Errorf("exporting schema snapshots: %w", err)
+	}
+	for path, bs := range snapshots {
+		out[path] = bs
+	}
+	return out, nil
+}
+
+// RolesForIntrospectionCheck returns every role that `hasura schema snapshot`
+// and `hasura schema diff` should take/compare a snapshot for: every role
+// named in disabled_for_roles plus every role declared elsewhere in the
+// metadata, since introspection is enabled by default for roles the file
+// doesn't mention at all.
+func (m *MetadataObject) RolesForIntrospectionCheck(metadata map[string]yaml.Node) []string {
+	roles := map[string]struct{}{}
+	for key, obj := range metadata {
+		if key == MetadataObjectKey {
+			continue
+		}
+		collectRoles(&obj, roles)
+	}
+	if node, ok := metadata[MetadataObjectKey]; ok {
+		if disabledForRoles := lookupMappingValue(&node, "disabled_for_roles"); disabledForRoles != nil {
+			for _, item := range disabledForRoles.Content {
+				if item.Kind == yaml.ScalarNode {
+					roles[item.Value] = struct{}{}
+				}
+			}
+		}
+	}
+	out := make([]string, 0, len(roles))
+	for role := range roles {
+		out = append(out, role)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// IntrospectionDisabledForRole reports whether role is listed in
+// disabled_for_roles, i.e. whether schema drift for it should be exempt from
+// the --schema-drift check performed by the `hasura schema diff` command.
+func (m *MetadataObject) IntrospectionDisabledForRole(metadata map[string]yaml.Node, role string) bool {
+	node, ok := metadata[MetadataObjectKey]
+	if !ok {
+		return false
+	}
+	disabledForRoles := lookupMappingValue(&node, "disabled_for_roles")
+	if disabledForRoles == nil {
+		return false
+	}
+	for _, item := range disabledForRoles.Content {
+		if item.Kind == yaml.ScalarNode && item.Value == role {
+			return true
+		}
+	}
+	return false
+}
+
+// UnknownRoleError is returned (wrapped in a ValidationError) by Validate
+// when disabled_for_roles names a role that is not declared anywhere else in
+// the assembled metadata. Line/Column point into graphql_schema_introspection.yaml.
+type UnknownRoleError struct {
+	Role   string
+	Line   int
+	Column int
+}
+
+func (e *UnknownRoleError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: role %q listed in disabled_for_roles is not declared anywhere else in the metadata", MetadataFilename, e.Line, e.Column, e.Role)
+}
+
+// ValidationError collects every UnknownRoleError found during Validate so a
+// user sees all unknown roles in one pass instead of fixing them one at a time.
+type ValidationError struct {
+	Errors []*UnknownRoleError
+}
+
+func (e *ValidationError) Error() string {
+	msg := fmt.Sprintf("found %d unknown role(s) in %s:", len(e.Errors), MetadataFilename)
+	for _, err := range e.Errors {
+		msg += "\n  " + err.Error()
+	}
+	return msg
+}
+
+// Validate cross-checks disabled_for_roles against every role declared
+// elsewhere in the assembled metadata, e.g. actions.permissions,
+// <source>.tables[*].{select,insert,update,delete}_permissions,
+// inherited_roles, query_collections/allow_list scopes and cron_triggers.
+// It catches silent typos where introspection stays enabled for a role
+// because the configured name does not match any declared permission.
+func (m *MetadataObject) Validate(metadata map[string]yaml.Node) error {
+	node, ok := metadata[MetadataObjectKey]
+	if !ok {
+		return nil
+	}
+	disabledForRoles := lookupMappingValue(&node, "disabled_for_roles")
+	if disabledForRoles == nil || disabledForRoles.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	declaredRoles := map[string]struct{}{}
+	for key, obj := range metadata {
+		if key == MetadataObjectKey {
+			continue
+		}
+		collectRoles(&obj, declaredRoles)
+	}
+
+	var unknown []*UnknownRoleError
+	for _, item := range disabledForRoles.Content {
+		if item.Kind != yaml.ScalarNode {
+			continue
+		}
+		if _, ok := declaredRoles[item.Value]; !ok {
+			unknown = append(unknown, &UnknownRoleError{
+				Role:   item.Value,
+				Line:   item.Line,
+				Column: item.Column,
+			})
+		}
+	}
+	if len(unknown) > 0 {
+		return &ValidationError{Errors: unknown}
+	}
+	return nil
+}
+
+// lookupMappingValue returns the value node for key in a mapping, unwrapping
+// a leading document node if present. It returns nil when node isn't a
+// mapping or doesn't contain key.
+func lookupMappingValue(node *yaml.Node, key string) *yaml.Node {
+	target := node
+	if target.Kind == yaml.DocumentNode && len(target.Content) > 0 {
+		target = target.Content[0]
+	}
+	if target.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(target.Content); i += 2 {
+		if target.Content[i].Value == key {
+			return target.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// collectRoles walks node looking for the shapes role declarations take
+// across the metadata directory: a scalar "role"/"role_name" key (actions
+// permissions, table permissions, inherited_roles) or a "roles"/"role_set"
+// sequence (allow_list/query_collections scopes, inherited_roles role sets).
+func collectRoles(node *yaml.Node, roles map[string]struct{}) {
+	if node == nil {
+		return
+	}
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, c := range node.Content {
+			collectRoles(c, roles)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, val := node.Content[i], node.Content[i+1]
+			switch key.Value {
+			case "role", "role_name":
+				if val.Kind == yaml.ScalarNode && val.Value != "" {
+					roles[val.Value] = struct{}{}
+				}
+			case "roles", "role_set":
+				if val.Kind == yaml.SequenceNode {
+					for _, item := range val.Content {
+						if item.Kind == yaml.ScalarNode {
+							roles[item.Value] = struct{}{}
+						}
+					}
+				}
+			}
+			collectRoles(val, roles)
+		}
+	}
+}
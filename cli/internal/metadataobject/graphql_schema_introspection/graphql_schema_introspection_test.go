@@ -2,16 +2,20 @@ package graphqlschemaintrospection
 
 import (
 	"io/ioutil"
+	"path/filepath"
 	"testing"
 
 	goyaml "github.com/goccy/go-yaml"
 	"github.com/hasura/graphql-engine/cli/v2/internal/metadatautil"
+	"github.com/hasura/graphql-engine/cli/v2/internal/testutil/goldens"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v3"
 )
 
+func TestMain(m *testing.M) { goldens.TestMain(m) }
+
 func TestMetadataObject_Build(t *testing.T) {
 	type fields struct {
 		MetadataDir string
@@ -50,11 +54,7 @@ func TestMetadataObject_Build(t *testing.T) {
 				jsonbs, err := goyaml.YAMLToJSON(gotbs)
 				assert.NoError(t, err)
 
-				// uncomment following lines to update golden file
-				//assert.NoError(t, ioutil.WriteFile(tt.wantGolden, jsonbs, os.ModePerm))
-				wantbs, err := ioutil.ReadFile(tt.wantGolden)
-				assert.NoError(t, err)
-				assert.Equal(t, string(wantbs), string(jsonbs))
+				goldens.CompareOrUpdate(t, tt.wantGolden, jsonbs)
 			}
 		})
 	}
@@ -114,13 +114,65 @@ func TestMetadataObject_Export(t *testing.T) {
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
-				for k, v := range got {
+				for k := range got {
 					assert.Contains(t, tt.want, k)
-					// uncomment to update golden files
-					//assert.NoError(t, ioutil.WriteFile(fmt.Sprintf("testdata/export_test/%v/want.%v", tt.id, filepath.Base(k)), v, os.ModePerm))
-					assert.Equalf(t, string(tt.want[k]), string(v), "%v", k)
 				}
+				goldens.CompareDirOrUpdate(t, filepath.Join("testdata", "export_test", tt.id), "want.", got)
+			}
+		})
+	}
+}
+
+func mustUnmarshalNode(t *testing.T, s string) yaml.Node {
+	t.Helper()
+	var node yaml.Node
+	assert.NoError(t, yaml.Unmarshal([]byte(s), &node))
+	return node
+}
+
+func TestMetadataObject_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		metadata    map[string]yaml.Node
+		wantErr     bool
+		wantUnknown []string
+	}{
+		{
+			"all disabled roles are declared elsewhere",
+			map[string]yaml.Node{
+				MetadataObjectKey:     mustUnmarshalNode(t, `disabled_for_roles: [user, manager]`),
+				"actions.yaml":        mustUnmarshalNode(t, `actions: [{name: doThing, permissions: [{role: user}]}]`),
+				"inherited_roles.yaml": mustUnmarshalNode(t, `[{role_name: manager, role_set: [user, moderator]}]`),
+			},
+			false,
+			nil,
+		},
+		{
+			"typoed role is reported",
+			map[string]yaml.Node{
+				MetadataObjectKey: mustUnmarshalNode(t, `disabled_for_roles: [user, usre]`),
+				"actions.yaml":    mustUnmarshalNode(t, `actions: [{name: doThing, permissions: [{role: user}]}]`),
+			},
+			true,
+			[]string{"usre"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &MetadataObject{logger: logrus.New()}
+			err := obj.Validate(tt.metadata)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			var validationErr *ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+			var gotUnknown []string
+			for _, e := range validationErr.Errors {
+				gotUnknown = append(gotUnknown, e.Role)
 			}
+			assert.Equal(t, tt.wantUnknown, gotUnknown)
 		})
 	}
 }
@@ -0,0 +1,125 @@
+// Package schemasnapshot stores, canonicalizes and diffs GraphQL
+// introspection results per role, so the standalone `hasura schema diff`
+// command can detect schema drift for roles whose introspection is not
+// disabled. It is not yet invoked automatically by `hasura metadata apply`.
+package schemasnapshot
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// Schema is the subset of the standard GraphQL introspection result this
+// package cares about. Fields are tagged to round-trip the same JSON shape
+// the engine's introspection query returns.
+type Schema struct {
+	QueryType        *TypeRef   `json:"queryType,omitempty"`
+	MutationType     *TypeRef   `json:"mutationType,omitempty"`
+	SubscriptionType *TypeRef   `json:"subscriptionType,omitempty"`
+	Types            []Type     `json:"types"`
+	Directives       []Directive `json:"directives"`
+}
+
+// TypeRef is a GraphQL type reference as returned by introspection, recursing
+// through NON_NULL/LIST wrappers via OfType so the full wrapped type (e.g.
+// "[String!]!") is available, not just the innermost named type.
+type TypeRef struct {
+	Kind   string   `json:"kind,omitempty"`
+	Name   string   `json:"name,omitempty"`
+	OfType *TypeRef `json:"ofType,omitempty"`
+}
+
+// String renders ref the way GraphQL SDL would, e.g. "[String!]!".
+func (ref *TypeRef) String() string {
+	if ref == nil {
+		return ""
+	}
+	switch ref.Kind {
+	case "NON_NULL":
+		return ref.OfType.String() + "!"
+	case "LIST":
+		return "[" + ref.OfType.String() + "]"
+	default:
+		return ref.Name
+	}
+}
+
+type Type struct {
+	Kind          string      `json:"kind"`
+	Name          string      `json:"name"`
+	Description   string      `json:"description,omitempty"`
+	Fields        []Field     `json:"fields,omitempty"`
+	InputFields    []InputValue `json:"inputFields,omitempty"`
+	Interfaces    []TypeRef   `json:"interfaces,omitempty"`
+	EnumValues    []EnumValue `json:"enumValues,omitempty"`
+	PossibleTypes []TypeRef   `json:"possibleTypes,omitempty"`
+}
+
+type Field struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Args        []InputValue `json:"args,omitempty"`
+	Type        *TypeRef     `json:"type,omitempty"`
+	IsDeprecated bool        `json:"isDeprecated,omitempty"`
+}
+
+type InputValue struct {
+	Name         string   `json:"name"`
+	Description  string   `json:"description,omitempty"`
+	Type         *TypeRef `json:"type,omitempty"`
+	DefaultValue string   `json:"defaultValue,omitempty"`
+}
+
+type EnumValue struct {
+	Name         string `json:"name"`
+	Description  string `json:"description,omitempty"`
+	IsDeprecated bool   `json:"isDeprecated,omitempty"`
+}
+
+type Directive struct {
+	Name string       `json:"name"`
+	Args []InputValue `json:"args,omitempty"`
+}
+
+// Canonicalize sorts every list in schema by name, recursively, so that two
+// introspection results that differ only in the server's arbitrary ordering
+// serialize to identical bytes. The input is the `data` object of the
+// introspection query response (the object holding `__schema`); the output is
+// the `__schema` object alone.
+func Canonicalize(introspectionResult []byte) ([]byte, error) {
+	var wrapper struct {
+		Schema Schema `json:"__schema"`
+	}
+	if err := json.Unmarshal(introspectionResult, &wrapper); err != nil {
+		return nil, err
+	}
+	schema := wrapper.Schema
+	sortSchema(&schema)
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+func sortSchema(s *Schema) {
+	sort.Slice(s.Types, func(i, j int) bool { return s.Types[i].Name < s.Types[j].Name })
+	for i := range s.Types {
+		sortType(&s.Types[i])
+	}
+	sort.Slice(s.Directives, func(i, j int) bool { return s.Directives[i].Name < s.Directives[j].Name })
+	for i := range s.Directives {
+		sortInputValues(s.Directives[i].Args)
+	}
+}
+
+func sortType(t *Type) {
+	sort.Slice(t.Fields, func(i, j int) bool { return t.Fields[i].Name < t.Fields[j].Name })
+	for i := range t.Fields {
+		sortInputValues(t.Fields[i].Args)
+	}
+	sortInputValues(t.InputFields)
+	sort.Slice(t.Interfaces, func(i, j int) bool { return t.Interfaces[i].Name < t.Interfaces[j].Name })
+	sort.Slice(t.EnumValues, func(i, j int) bool { return t.EnumValues[i].Name < t.EnumValues[j].Name })
+	sort.Slice(t.PossibleTypes, func(i, j int) bool { return t.PossibleTypes[i].Name < t.PossibleTypes[j].Name })
+}
+
+func sortInputValues(vs []InputValue) {
+	sort.Slice(vs, func(i, j int) bool { return vs[i].Name < vs[j].Name })
+}
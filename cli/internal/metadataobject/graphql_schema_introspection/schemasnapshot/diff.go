@@ -0,0 +1,178 @@
+package schemasnapshot
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// DriftMode controls how Diff results are surfaced by the `hasura schema
+// diff` command.
+type DriftMode string
+
+const (
+	DriftModeWarn  DriftMode = "warn"
+	DriftModeError DriftMode = "error"
+	DriftModeIgnore DriftMode = "ignore"
+)
+
+// Diff is the set of changes found between two canonicalized snapshots of a
+// role's schema, both breaking and non-breaking (see IsBreaking).
+type Diff struct {
+	Role             string   `json:"role"`
+	RemovedTypes     []string `json:"removed_types,omitempty"`
+	AddedTypes       []string `json:"added_types,omitempty"`
+	RemovedFields    []string `json:"removed_fields,omitempty"`
+	AddedFields      []string `json:"added_fields,omitempty"`
+	ChangedFieldTypes []string `json:"changed_field_types,omitempty"`
+	RemovedArgs      []string `json:"removed_args,omitempty"`
+	AddedArgs        []string `json:"added_args,omitempty"`
+	ChangedArgTypes  []string `json:"changed_arg_types,omitempty"`
+	RemovedDirectives []string `json:"removed_directives,omitempty"`
+	AddedDirectives  []string `json:"added_directives,omitempty"`
+}
+
+// IsBreaking reports whether the diff contains changes that could break an
+// existing client: removals and type changes (e.g. String -> Int, or a
+// field/arg becoming non-null) are breaking, additions are not.
+func (d *Diff) IsBreaking() bool {
+	return len(d.RemovedTypes) > 0 || len(d.RemovedFields) > 0 ||
+		len(d.RemovedArgs) > 0 || len(d.RemovedDirectives) > 0 ||
+		len(d.ChangedFieldTypes) > 0 || len(d.ChangedArgTypes) > 0
+}
+
+func (d *Diff) String() string {
+	if d.IsBreaking() {
+		return fmt.Sprintf(
+			"schema drift for role %q: -%d types +%d types, -%d fields +%d fields (%d type changed), -%d args +%d args (%d type changed), -%d directives +%d directives",
+			d.Role, len(d.RemovedTypes), len(d.AddedTypes), len(d.RemovedFields), len(d.AddedFields), len(d.ChangedFieldTypes),
+			len(d.RemovedArgs), len(d.AddedArgs), len(d.ChangedArgTypes), len(d.RemovedDirectives), len(d.AddedDirectives),
+		)
+	}
+	return fmt.Sprintf("no breaking schema drift for role %q", d.Role)
+}
+
+// CompareSnapshots diffs two canonicalized snapshots (as produced by
+// Canonicalize) for role, reporting added/removed types, fields, arguments
+// and directives.
+func CompareSnapshots(role string, oldSnapshot, newSnapshot []byte) (*Diff, error) {
+	var oldSchema, newSchema Schema
+	if len(oldSnapshot) > 0 {
+		if err := json.Unmarshal(oldSnapshot, &oldSchema); err != nil {
+			return nil, fmt.Errorf("parsing existing snapshot for role %q: %w", role, err)
+		}
+	}
+	if err := json.Unmarshal(newSnapshot, &newSchema); err != nil {
+		return nil, fmt.Errorf("parsing new snapshot for role %q: %w", role, err)
+	}
+
+	diff := &Diff{Role: role}
+
+	oldTypes := typesByName(oldSchema.Types)
+	newTypes := typesByName(newSchema.Types)
+	for name := range oldTypes {
+		if _, ok := newTypes[name]; !ok {
+			diff.RemovedTypes = append(diff.RemovedTypes, name)
+		}
+	}
+	for name := range newTypes {
+		if _, ok := oldTypes[name]; !ok {
+			diff.AddedTypes = append(diff.AddedTypes, name)
+		}
+	}
+
+	for name, oldType := range oldTypes {
+		newType, ok := newTypes[name]
+		if !ok {
+			continue
+		}
+		diffFields(name, oldType.Fields, newType.Fields, diff)
+	}
+
+	oldDirectives := directivesByName(oldSchema.Directives)
+	newDirectives := directivesByName(newSchema.Directives)
+	for name := range oldDirectives {
+		if _, ok := newDirectives[name]; !ok {
+			diff.RemovedDirectives = append(diff.RemovedDirectives, name)
+		}
+	}
+	for name := range newDirectives {
+		if _, ok := oldDirectives[name]; !ok {
+			diff.AddedDirectives = append(diff.AddedDirectives, name)
+		}
+	}
+
+	return diff, nil
+}
+
+func diffFields(typeName string, oldFields, newFields []Field, diff *Diff) {
+	oldByName := fieldsByName(oldFields)
+	newByName := fieldsByName(newFields)
+	for name, oldField := range oldByName {
+		newField, ok := newByName[name]
+		if !ok {
+			diff.RemovedFields = append(diff.RemovedFields, fmt.Sprintf("%s.%s", typeName, name))
+			continue
+		}
+		if oldField.Type.String() != newField.Type.String() {
+			diff.ChangedFieldTypes = append(diff.ChangedFieldTypes, fmt.Sprintf("%s.%s: %s -> %s", typeName, name, oldField.Type.String(), newField.Type.String()))
+		}
+		diffArgs(typeName, name, oldField.Args, newField.Args, diff)
+	}
+	for name := range newByName {
+		if _, ok := oldByName[name]; !ok {
+			diff.AddedFields = append(diff.AddedFields, fmt.Sprintf("%s.%s", typeName, name))
+		}
+	}
+}
+
+func diffArgs(typeName, fieldName string, oldArgs, newArgs []InputValue, diff *Diff) {
+	oldByName := argsByName(oldArgs)
+	newByName := argsByName(newArgs)
+	for name, oldArg := range oldByName {
+		newArg, ok := newByName[name]
+		if !ok {
+			diff.RemovedArgs = append(diff.RemovedArgs, fmt.Sprintf("%s.%s(%s)", typeName, fieldName, name))
+			continue
+		}
+		if oldArg.Type.String() != newArg.Type.String() {
+			diff.ChangedArgTypes = append(diff.ChangedArgTypes, fmt.Sprintf("%s.%s(%s): %s -> %s", typeName, fieldName, name, oldArg.Type.String(), newArg.Type.String()))
+		}
+	}
+	for name := range newByName {
+		if _, ok := oldByName[name]; !ok {
+			diff.AddedArgs = append(diff.AddedArgs, fmt.Sprintf("%s.%s(%s)", typeName, fieldName, name))
+		}
+	}
+}
+
+func typesByName(types []Type) map[string]Type {
+	out := make(map[string]Type, len(types))
+	for _, t := range types {
+		out[t.Name] = t
+	}
+	return out
+}
+
+func fieldsByName(fields []Field) map[string]Field {
+	out := make(map[string]Field, len(fields))
+	for _, f := range fields {
+		out[f.Name] = f
+	}
+	return out
+}
+
+func argsByName(args []InputValue) map[string]InputValue {
+	out := make(map[string]InputValue, len(args))
+	for _, a := range args {
+		out[a.Name] = a
+	}
+	return out
+}
+
+func directivesByName(directives []Directive) map[string]Directive {
+	out := make(map[string]Directive, len(directives))
+	for _, d := range directives {
+		out[d.Name] = d
+	}
+	return out
+}
@@ -0,0 +1,75 @@
+package schemasnapshot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalize_sortsRegardlessOfServerOrdering(t *testing.T) {
+	a := []byte(`{"__schema":{"types":[{"kind":"OBJECT","name":"B","fields":[{"name":"z"},{"name":"a"}]},{"kind":"OBJECT","name":"A"}],"directives":[{"name":"b"},{"name":"a"}]}}`)
+	b := []byte(`{"__schema":{"types":[{"kind":"OBJECT","name":"A"},{"kind":"OBJECT","name":"B","fields":[{"name":"a"},{"name":"z"}]}],"directives":[{"name":"a"},{"name":"b"}]}}`)
+
+	gotA, err := Canonicalize(a)
+	assert.NoError(t, err)
+	gotB, err := Canonicalize(b)
+	assert.NoError(t, err)
+	assert.Equal(t, string(gotA), string(gotB))
+}
+
+func TestCompareSnapshots(t *testing.T) {
+	old := []byte(`{"__schema":{"types":[{"kind":"OBJECT","name":"User","fields":[{"name":"id"},{"name":"email"}]}],"directives":[{"name":"include"}]}}`)
+	new := []byte(`{"__schema":{"types":[{"kind":"OBJECT","name":"User","fields":[{"name":"id"},{"name":"phone"}]},{"kind":"OBJECT","name":"Post"}],"directives":[{"name":"include"}]}}`)
+
+	oldCanonical, err := Canonicalize(old)
+	assert.NoError(t, err)
+	newCanonical, err := Canonicalize(new)
+	assert.NoError(t, err)
+
+	diff, err := CompareSnapshots("user", oldCanonical, newCanonical)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Post"}, diff.AddedTypes)
+	assert.Equal(t, []string{"User.email"}, diff.RemovedFields)
+	assert.Equal(t, []string{"User.phone"}, diff.AddedFields)
+	assert.True(t, diff.IsBreaking())
+}
+
+func TestCompareSnapshots_fieldTypeChangeIsBreaking(t *testing.T) {
+	old := []byte(`{"__schema":{"types":[{"kind":"OBJECT","name":"User","fields":[{"name":"email","type":{"kind":"SCALAR","name":"String"}}]}]}}`)
+	new := []byte(`{"__schema":{"types":[{"kind":"OBJECT","name":"User","fields":[{"name":"email","type":{"kind":"NON_NULL","ofType":{"kind":"SCALAR","name":"String"}}}]}]}}`)
+
+	oldCanonical, err := Canonicalize(old)
+	assert.NoError(t, err)
+	newCanonical, err := Canonicalize(new)
+	assert.NoError(t, err)
+
+	diff, err := CompareSnapshots("user", oldCanonical, newCanonical)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"User.email: String -> String!"}, diff.ChangedFieldTypes)
+	assert.True(t, diff.IsBreaking())
+}
+
+func TestCompareSnapshots_argTypeChangeIsBreaking(t *testing.T) {
+	old := []byte(`{"__schema":{"types":[{"kind":"OBJECT","name":"Query","fields":[{"name":"user","args":[{"name":"id","type":{"kind":"SCALAR","name":"String"}}]}]}]}}`)
+	new := []byte(`{"__schema":{"types":[{"kind":"OBJECT","name":"Query","fields":[{"name":"user","args":[{"name":"id","type":{"kind":"SCALAR","name":"Int"}}]}]}]}}`)
+
+	oldCanonical, err := Canonicalize(old)
+	assert.NoError(t, err)
+	newCanonical, err := Canonicalize(new)
+	assert.NoError(t, err)
+
+	diff, err := CompareSnapshots("user", oldCanonical, newCanonical)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Query.user(id): String -> Int"}, diff.ChangedArgTypes)
+	assert.True(t, diff.IsBreaking())
+}
+
+func TestCompareSnapshots_noDrift(t *testing.T) {
+	snapshot := []byte(`{"__schema":{"types":[{"kind":"OBJECT","name":"User","fields":[{"name":"id"}]}]}}`)
+	canonical, err := Canonicalize(snapshot)
+	assert.NoError(t, err)
+
+	diff, err := CompareSnapshots("user", canonical, canonical)
+	assert.NoError(t, err)
+	assert.False(t, diff.IsBreaking())
+}
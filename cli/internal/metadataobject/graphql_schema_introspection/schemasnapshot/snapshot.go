@@ -0,0 +1,72 @@
+package schemasnapshot
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// DirName is the directory, relative to the metadata directory, that
+// per-role schema snapshots are stored under.
+const DirName = "schema_snapshots"
+
+// PathForRole returns the path a role's snapshot is stored at:
+// metadataDir joined with schema_snapshots/<role>.json.
+func PathForRole(metadataDir, role string) string {
+	return filepath.Join(metadataDir, DirName, fmt.Sprintf("%s.json", role))
+}
+
+// Load reads and returns the stored snapshot for role, or (nil, nil) if no
+// snapshot has been taken for that role yet.
+func Load(metadataDir, role string) ([]byte, error) {
+	bs, err := ioutil.ReadFile(PathForRole(metadataDir, role))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return bs, nil
+}
+
+// Save canonicalizes introspectionResult and writes it as the snapshot for
+// role, creating the schema_snapshots directory if necessary.
+func Save(metadataDir, role string, introspectionResult []byte) error {
+	canonical, err := Canonicalize(introspectionResult)
+	if err != nil {
+		return fmt.Errorf("canonicalizing introspection result for role %q: %w", role, err)
+	}
+	path := PathForRole(metadataDir, role)
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, canonical, 0644)
+}
+
+// Export returns every stored snapshot under metadataDir, keyed by their
+// full path, so callers can fold them into a MetadataObject.Export result
+// and have them travel with the rest of the metadata directory.
+func Export(metadataDir string) (map[string][]byte, error) {
+	dir := filepath.Join(metadataDir, DirName)
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string][]byte{}, nil
+		}
+		return nil, err
+	}
+	out := map[string][]byte{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		bs, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		out[path] = bs
+	}
+	return out, nil
+}
@@ -0,0 +1,68 @@
+// Package goldens provides a small shared harness for tests that compare
+// generated output against checked-in fixture ("golden") files.
+//
+// Packages under cli/internal/metadataobject/... historically kept a
+// commented-out ioutil.WriteFile call next to the assertion that developers
+// had to manually uncomment whenever a fixture needed refreshing. This
+// package replaces that pattern with a single `-update-goldens` flag: tests
+// call CompareOrUpdate/CompareDirOrUpdate, and running
+//
+//	go test ./internal/metadataobject/graphql_schema_introspection -update-goldens
+//
+// rewrites every fixture touched by that package's tests instead of diffing
+// against it. The flag is defined only in the test binaries of packages that
+// import goldens; a package that does not import it rejects the flag with
+// "flag provided but not defined", so `go test ./... -update-goldens` fails
+// for those packages.
+package goldens
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// update is registered on the default flag set when goldens is imported, and
+// parsed by TestMain. It is package-level (rather than parsed fresh per call) so every test in a
+// package shares the same flag instance.
+var update = flag.Bool("update-goldens", false, "write golden files instead of comparing against them")
+
+// TestMain parses the -update-goldens flag and then runs the package's
+// tests. Packages that want golden-file support must add:
+//
+//	func TestMain(m *testing.M) { goldens.TestMain(m) }
+func TestMain(m *testing.M) {
+	flag.Parse()
+	os.Exit(m.Run())
+}
+
+// CompareOrUpdate compares got against the contents of the golden file at
+// path. When -update-goldens is set, it writes got to path instead.
+func CompareOrUpdate(t *testing.T, path string, got []byte) {
+	t.Helper()
+	if *update {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		assert.NoError(t, ioutil.WriteFile(path, got, 0644))
+		return
+	}
+	want, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
+
+// CompareDirOrUpdate compares each entry of got against the golden file
+// "<prefix><basename>" under dir, where basename is filepath.Base(name) —
+// e.g. the key testdata/metadata/graphql_schema_introspection.yaml with
+// prefix "want." is compared against dir/want.graphql_schema_introspection.yaml.
+// When -update-goldens is set, it writes every entry instead.
+func CompareDirOrUpdate(t *testing.T, dir, prefix string, got map[string][]byte) {
+	t.Helper()
+	for name, bs := range got {
+		path := filepath.Join(dir, prefix+filepath.Base(name))
+		CompareOrUpdate(t, path, bs)
+	}
+}